@@ -0,0 +1,74 @@
+// Package backoff implementa un backoff exponencial con jitter completo,
+// siguiendo el mismo esquema que las bibliotecas de backoff habituales en
+// clientes de red Go: intervalo inicial, multiplicador, tope máximo y un
+// tiempo total transcurrido opcional.
+package backoff
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Config parametriza un Backoff.
+type Config struct {
+	InitialInterval time.Duration // intervalo antes de aplicar jitter en el primer reintento
+	Multiplier      float64       // factor de crecimiento aplicado en cada NextBackOff
+	MaxInterval     time.Duration // tope del intervalo, antes de jitter
+	MaxElapsedTime  time.Duration // tiempo total tras el que NextBackOff devuelve Stop; 0 = sin límite
+}
+
+// DefaultConfig son los valores recomendados para el bucle de reconexión del
+// cliente: 500ms iniciales, factor 1.5, tope de 30s, sin límite de tiempo
+// total.
+func DefaultConfig() Config {
+	return Config{
+		InitialInterval: 500 * time.Millisecond,
+		Multiplier:      1.5,
+		MaxInterval:     30 * time.Second,
+		MaxElapsedTime:  0,
+	}
+}
+
+// Stop se devuelve en NextBackOff cuando se ha superado MaxElapsedTime.
+const Stop time.Duration = -1
+
+// Backoff calcula intervalos de espera crecientes con jitter completo entre
+// reintentos.
+type Backoff struct {
+	cfg             Config
+	currentInterval time.Duration
+	startedAt       time.Time
+}
+
+// NewExponential crea un Backoff a partir de cfg.
+func NewExponential(cfg Config) *Backoff {
+	b := &Backoff{cfg: cfg}
+	b.Reset()
+	return b
+}
+
+// Reset vuelve al intervalo inicial y reinicia el contador de tiempo total
+// transcurrido.
+func (b *Backoff) Reset() {
+	b.currentInterval = b.cfg.InitialInterval
+	b.startedAt = time.Now()
+}
+
+// NextBackOff devuelve cuánto esperar antes del siguiente reintento y hace
+// crecer el intervalo para la próxima llamada. Devuelve Stop si se ha
+// superado MaxElapsedTime.
+func (b *Backoff) NextBackOff() time.Duration {
+	if b.cfg.MaxElapsedTime > 0 && time.Since(b.startedAt) > b.cfg.MaxElapsedTime {
+		return Stop
+	}
+
+	wait := time.Duration(rand.Float64() * float64(b.currentInterval)) // jitter completo
+
+	next := time.Duration(float64(b.currentInterval) * b.cfg.Multiplier)
+	if next > b.cfg.MaxInterval {
+		next = b.cfg.MaxInterval
+	}
+	b.currentInterval = next
+
+	return wait
+}
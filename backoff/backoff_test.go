@@ -0,0 +1,78 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextBackOffGrowsAndRespectsMax(t *testing.T) {
+	cfg := Config{
+		InitialInterval: 10 * time.Millisecond,
+		Multiplier:      2,
+		MaxInterval:     100 * time.Millisecond,
+	}
+	b := NewExponential(cfg)
+
+	prevCeiling := cfg.InitialInterval
+	for i := 0; i < 20; i++ {
+		wait := b.NextBackOff()
+		if wait < 0 {
+			t.Fatalf("iteration %d: unexpected Stop with no MaxElapsedTime", i)
+		}
+		if wait > prevCeiling {
+			t.Fatalf("iteration %d: wait %v exceeded the interval ceiling %v it was drawn from", i, wait, prevCeiling)
+		}
+		if wait > cfg.MaxInterval {
+			t.Fatalf("iteration %d: wait %v exceeded MaxInterval %v", i, wait, cfg.MaxInterval)
+		}
+		prevCeiling *= 2
+		if prevCeiling > cfg.MaxInterval {
+			prevCeiling = cfg.MaxInterval
+		}
+	}
+}
+
+func TestNextBackOffJitterVaries(t *testing.T) {
+	cfg := Config{
+		InitialInterval: time.Second,
+		Multiplier:      1, // mantiene el mismo techo en cada llamada
+		MaxInterval:     time.Second,
+	}
+	b := NewExponential(cfg)
+
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 50; i++ {
+		seen[b.NextBackOff()] = true
+	}
+	if len(seen) < 2 {
+		t.Fatalf("expected jitter to produce varying waits, got only %d distinct value(s)", len(seen))
+	}
+}
+
+func TestNextBackOffStopsAfterMaxElapsedTime(t *testing.T) {
+	b := NewExponential(Config{
+		InitialInterval: time.Millisecond,
+		Multiplier:      1.5,
+		MaxInterval:     time.Second,
+		MaxElapsedTime:  10 * time.Millisecond,
+	})
+	time.Sleep(20 * time.Millisecond)
+	if wait := b.NextBackOff(); wait != Stop {
+		t.Fatalf("expected Stop after MaxElapsedTime, got %v", wait)
+	}
+}
+
+func TestReset(t *testing.T) {
+	b := NewExponential(Config{
+		InitialInterval: 10 * time.Millisecond,
+		Multiplier:      2,
+		MaxInterval:     time.Second,
+	})
+	for i := 0; i < 5; i++ {
+		b.NextBackOff()
+	}
+	b.Reset()
+	if b.currentInterval != 10*time.Millisecond {
+		t.Fatalf("expected Reset to restore the initial interval, got %v", b.currentInterval)
+	}
+}
@@ -1,117 +1,237 @@
 package main
 
 import (
-	"bytes"
 	"container/heap"
 	"fmt"
 	"io"
-	"log"
 	"math/rand"
 	"net"
+	"os"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
+
+	"practica4_ssoo_dist/backoff"
+	"practica4_ssoo_dist/logging"
+	"practica4_ssoo_dist/metrics"
+	"practica4_ssoo_dist/model"
+	"practica4_ssoo_dist/persist"
+	"practica4_ssoo_dist/scheduler"
+)
+
+// metricsAddrEnv es la variable de entorno que fija dónde sirve /metrics;
+// si no está definida se usa metricsAddrDefault.
+const (
+	metricsAddrEnv     = "METRICS_ADDR"
+	metricsAddrDefault = ":9100"
+)
+
+// logFileEnv y logSyslogTagEnv activan, opcionalmente, los writers de
+// logging.L con fichero rotado y/o syslog; si no están definidas sólo se
+// loggea a stdout como hasta ahora.
+const (
+	logFileEnv      = "LOG_FILE"
+	logFileMaxBytes = 10 * 1024 * 1024
+	logSyslogTagEnv = "LOG_SYSLOG_TAG"
 )
 
+// persistDir es el directorio donde viven el WAL y los snapshots del estado.
+const persistDir = "data"
+
 var (
-	buf               bytes.Buffer
-	logger            = log.New(&buf, "logger: ", log.Lshortfile)
-	msg               string
 	estadoActual      int
 	mu                sync.Mutex
-	colaAviones       AvionHeap                // Cola de prioridad de aviones
+	colaAviones       model.AvionHeap          // Cola de prioridad de aviones
 	pistasDisponibles = make(chan struct{}, 3) // Canal para manejar pistas disponibles (máximo 3)
 	procesar          bool
 	detenerProceso    bool
+	wal               *persist.WAL
+	sched             scheduler.Scheduler
 )
 
-// Estructura que representa un avión con sus atributos
-type Avion struct {
-	id           int
-	categoria    string
-	numPasajeros int
-	prioridad    int // Prioridad en la cola
+// Inicialización del programa
+//
+// Si hay un WAL o snapshot en persistDir de una ejecución anterior, se
+// reconstruye el estado exactamente como estaba (cola, pistas y estado del
+// aeropuerto) en vez de generar datos de prueba.
+func init() {
+	rand.Seed(time.Now().UnixNano()) // Semilla para números aleatorios
+
+	sched = scheduler.FromEnv()
+
+	w, err := persist.OpenWAL(persistDir)
+	if err != nil {
+		logging.L.Error("wal_open_failed", "error", err)
+		os.Exit(1)
+	}
+	wal = w
+
+	estado, err := persist.Recover(persistDir)
+	if err != nil {
+		logging.L.Error("wal_recover_failed", "error", err)
+		os.Exit(1)
+	}
+
+	heap.Init(&colaAviones) // Inicializa la cola de prioridad
+	if len(estado.Cola) > 0 || estado.EstadoActual != 0 {
+		for _, a := range estado.Cola {
+			heap.Push(&colaAviones, avionFromRecord(a))
+		}
+		estadoActual = estado.EstadoActual
+		for i := 0; i < estado.PistasDisponibles; i++ {
+			pistasDisponibles <- struct{}{}
+		}
+	} else {
+		for i := 0; i < 3; i++ {
+			pistasDisponibles <- struct{}{} // Inicializa 3 pistas disponibles
+		}
+		// Agrega aviones de prueba con diferentes categorías y prioridades.
+		// Cada uno se registra en el WAL igual que cualquier otro encolado,
+		// para que un kill -9 antes de la primera compactación no los pierda.
+		ahora := time.Now()
+		for i := 1; i <= 10; i++ {
+			sembrarAvion(model.Avion{ID: i, Categoria: "A", NumPasajeros: rand.Intn(50) + 101, Encolado: ahora})
+			sembrarAvion(model.Avion{ID: i + 10, Categoria: "B", NumPasajeros: rand.Intn(51) + 50, Encolado: ahora})
+			sembrarAvion(model.Avion{ID: i + 20, Categoria: "C", NumPasajeros: rand.Intn(50) + 1, Encolado: ahora})
+		}
+	}
+	procesar = false
+	detenerProceso = false
+	actualizarMetricasCola()
+	metrics.RunwayBusy.Set(float64(3 - len(pistasDisponibles)))
 }
 
-// Implementación de una cola de prioridad para los aviones
-type AvionHeap []Avion
+// avionToRecord / avionFromRecord convierten entre el model.Avion compartido
+// y el persist.AvionRecord que viaja por el WAL y los snapshots.
+func avionToRecord(avion model.Avion) persist.AvionRecord {
+	return persist.AvionRecord{
+		ID:           avion.ID,
+		Categoria:    avion.Categoria,
+		NumPasajeros: avion.NumPasajeros,
+		Prioridad:    avion.Prioridad,
+		Encolado:     avion.Encolado,
+		Deadline:     avion.Deadline,
+	}
+}
 
-func (h AvionHeap) Len() int           { return len(h) }
-func (h AvionHeap) Less(i, j int) bool { return h[i].prioridad > h[j].prioridad }
-func (h AvionHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func avionFromRecord(r persist.AvionRecord) model.Avion {
+	return model.Avion{
+		ID:           r.ID,
+		Categoria:    r.Categoria,
+		NumPasajeros: r.NumPasajeros,
+		Prioridad:    r.Prioridad,
+		Encolado:     r.Encolado,
+		Deadline:     r.Deadline,
+	}
+}
 
-// Agregar un avión a la cola
-func (h *AvionHeap) Push(x interface{}) {
-	*h = append(*h, x.(Avion))
+// sembrarAvion agrega avion a la cola y lo registra en el WAL como
+// PlaneEnqueued, igual que cualquier otro encolado: si no se loggeara, un
+// kill -9 antes de la primera compactación perdería los aviones de prueba
+// sin que Recover pudiera reconstruirlos.
+func sembrarAvion(avion model.Avion) {
+	heap.Push(&colaAviones, avion)
+	logWAL(persist.Entry{Type: persist.PlaneEnqueued, Avion: avionToRecord(avion)})
 }
 
-// Retirar un avión de la cola
-func (h *AvionHeap) Pop() interface{} {
-	old := *h
-	n := len(old)
-	x := old[n-1]
-	*h = old[0 : n-1]
-	return x
+// logWAL añade entry al WAL y fuerza su escritura a disco, de forma que cada
+// evento quede confirmado antes de que el código que lo generó continúe.
+func logWAL(entry persist.Entry) {
+	if err := wal.Append(entry); err != nil {
+		logging.L.Error("wal_append_failed", "error", err)
+		return
+	}
+	if err := wal.Sync(); err != nil {
+		logging.L.Error("wal_sync_failed", "error", err)
+	}
 }
 
-// Inicialización del programa
-func init() {
-	rand.Seed(time.Now().UnixNano()) // Semilla para números aleatorios
-	for i := 0; i < 3; i++ {
-		pistasDisponibles <- struct{}{} // Inicializa 3 pistas disponibles
+// configurarSalidasLog añade a logging.L los writers opcionales de fichero
+// rotado y/o syslog, cada uno activado por su propia variable de entorno.
+// Si ninguna está definida, logging.L sigue escribiendo solo a stdout.
+func configurarSalidasLog() {
+	if path := os.Getenv(logFileEnv); path != "" {
+		w, err := logging.NewRotatingFileWriter(path, logFileMaxBytes)
+		if err != nil {
+			logging.L.Error("log_file_open_failed", "error", err)
+		} else {
+			logging.L.AddOutput(w)
+		}
 	}
-	heap.Init(&colaAviones) // Inicializa la cola de prioridad
-	// Agrega aviones de prueba con diferentes categorías y prioridades
-	for i := 1; i <= 10; i++ {
-		heap.Push(&colaAviones, Avion{id: i, categoria: "A", numPasajeros: rand.Intn(50) + 101, prioridad: 0})
-		heap.Push(&colaAviones, Avion{id: i + 10, categoria: "B", numPasajeros: rand.Intn(51) + 50, prioridad: 0})
-		heap.Push(&colaAviones, Avion{id: i + 20, categoria: "C", numPasajeros: rand.Intn(50) + 1, prioridad: 0})
+	if tag := os.Getenv(logSyslogTagEnv); tag != "" {
+		w, err := logging.NewSyslogWriter(tag)
+		if err != nil {
+			logging.L.Error("log_syslog_open_failed", "error", err)
+		} else {
+			logging.L.AddOutput(w)
+		}
 	}
-	procesar = false
-	detenerProceso = false
 }
 
 // Punto de entrada principal
+//
+// La reconexión usa un backoff exponencial con jitter en vez de un sleep
+// fijo, tanto cuando falla el Dial como cuando leerMensajes vuelve por un
+// error de lectura, para no martillear al servidor durante una caída.
 func main() {
+	configurarSalidasLog()
+
+	go func() {
+		addr := os.Getenv(metricsAddrEnv)
+		if addr == "" {
+			addr = metricsAddrDefault
+		}
+		if err := metrics.Serve(addr); err != nil {
+			logging.L.Error("metrics_server_failed", "error", err)
+		}
+	}()
+
+	reconectar := backoff.NewExponential(backoff.DefaultConfig())
 	for {
 		// Intenta conectar con el servidor
 		conn, err := net.Dial("tcp", "localhost:8000")
 		if err != nil {
-			logger.Println("Error al conectar con el servidor:", err)
-			time.Sleep(2 * time.Second)
+			logging.L.Warn("dial_failed", "error", err)
+			time.Sleep(reconectar.NextBackOff())
 			continue
 		}
-		defer conn.Close()
+		reconectar.Reset()
 
 		// Procesa la cola en segundo plano
 		go procesarCola()
 		// Lee los mensajes del servidor
 		leerMensajes(conn)
+		conn.Close()
+		time.Sleep(reconectar.NextBackOff())
 	}
 }
 
 // Leer mensajes del servidor y manejar el estado del aeropuerto
+//
+// enaire (el único peer que escribe en este socket) envía enteros ASCII
+// terminados en '\n' y no se puede tocar ("NO MODIFICAR ESTE ARCHIVO"), así
+// que el cliente sigue leyendo ese protocolo de líneas.
 func leerMensajes(conn net.Conn) {
 	buf := make([]byte, 512)
 	for {
 		n, err := conn.Read(buf)
 		if err == io.EOF {
-			fmt.Println("Conexión cerrada por el servidor")
+			logging.L.Info("server_connection_closed")
 			return
 		}
 		if err != nil {
-			fmt.Println("Error al leer del servidor:", err)
+			logging.L.Warn("server_read_failed", "error", err)
 			return
 		}
 		if n > 0 {
-			msg = strings.TrimSpace(string(buf[:n]))
+			mensaje := strings.TrimSpace(string(buf[:n]))
 			// Intenta interpretar el mensaje como un estado
-			if estado, err := strconv.Atoi(msg); err == nil {
+			if estado, err := strconv.Atoi(mensaje); err == nil {
 				actualizarEstado(estado)
 				descripcionEstado(estado)
 			} else {
-				fmt.Println(msg)
+				logging.L.Debug("message_received", "msg", mensaje)
 			}
 		}
 	}
@@ -140,59 +260,33 @@ func actualizarEstado(estado int) {
 	defer mu.Unlock()
 
 	if estado == 7 || estado == 8 {
-		fmt.Println("Estado 7 u 8 recibido, se mantiene el estado actual:", estadoActual)
+		logging.L.Debug("estado_ignorado", "estado", estado, "estado_actual", estadoActual)
 		return
 	}
 
+	logWAL(persist.Entry{Type: persist.StateChanged, Estado: estado})
+	estadoAnterior := estadoActual
 	estadoActual = estado
 	procesar = estado >= 1 && estado <= 6 // Habilitar procesamiento según el estado
 	detenerProceso = false
-	fmt.Printf("Estado actualizado a: %d ", estadoActual)
+	logging.L.Info("state_transition", "from", estadoAnterior, "to", estadoActual)
+	metrics.StateTransitionsTotal.WithLabelValues(strconv.Itoa(estadoAnterior), strconv.Itoa(estadoActual)).Inc()
 	if procesar {
-		reordenarCola()
+		sched.Reorder(&colaAviones, model.State{Estado: estadoActual})
+		actualizarMetricasCola()
 	}
 }
 
-// Reorganiza la cola de prioridad de acuerdo al estado actual
-func reordenarCola() {
-	var nuevaCola AvionHeap
-	for colaAviones.Len() > 0 {
-		avion := heap.Pop(&colaAviones).(Avion)
-		avion.prioridad = calcularPrioridad(avion) // Recalcula la prioridad
-		heap.Push(&nuevaCola, avion)
+// actualizarMetricasCola refresca el gauge de profundidad de cola por
+// categoría. El llamador debe tener mu bloqueado.
+func actualizarMetricasCola() {
+	depths := map[string]int{}
+	for _, avion := range colaAviones {
+		depths[avion.Categoria]++
 	}
-	colaAviones = nuevaCola
-}
-
-// Calcula la prioridad de un avión basado en el estado actual
-func calcularPrioridad(avion Avion) int {
-	switch estadoActual {
-	case 1:
-		if avion.categoria == "A" {
-			return 1
-		}
-	case 2:
-		if avion.categoria == "B" {
-			return 1
-		}
-	case 3:
-		if avion.categoria == "C" {
-			return 1
-		}
-	case 4:
-		if avion.categoria == "A" {
-			return 2
-		}
-	case 5:
-		if avion.categoria == "B" {
-			return 2
-		}
-	case 6:
-		if avion.categoria == "C" {
-			return 2
-		}
+	for _, categoria := range []string{"A", "B", "C"} {
+		metrics.QueueDepth.WithLabelValues(categoria).Set(float64(depths[categoria]))
 	}
-	return 0
 }
 
 // Procesa la cola de aviones y asigna pistas disponibles
@@ -210,16 +304,48 @@ func procesarCola() {
 			continue
 		}
 
-		avion := heap.Pop(&colaAviones).(Avion)
-		if estadoActual >= 1 && estadoActual <= 3 && !esCategoriaValida(avion) {
-			fmt.Printf("Todos los aviones de la categoría %s han sido procesados.\n", categoriaEstado(estadoActual))
+		avion := colaAviones[0]
+		logWAL(persist.Entry{Type: persist.PlaneDequeued, Avion: avionToRecord(avion)})
+		avion = heap.Pop(&colaAviones).(model.Avion)
+		if !sched.Admissible(avion, model.State{Estado: estadoActual}) {
+			logging.L.Info("category_drained", "categoria", categoriaEstado(estadoActual))
+			metrics.PlaneRejectedTotal.WithLabelValues("category_mismatch").Inc()
+			logWAL(persist.Entry{Type: persist.PlaneEnqueued, Avion: avionToRecord(avion)})
 			heap.Push(&colaAviones, avion) // Reinsertar el avión en la cola
+			actualizarMetricasCola()
 			detenerProceso = true
 			mu.Unlock()
 			continue
 		}
+		actualizarMetricasCola()
 		mu.Unlock()
+		if !avion.Encolado.IsZero() {
+			metrics.RunwayWaitSeconds.Observe(time.Since(avion.Encolado).Seconds())
+		}
 		usarPista(avion)
+		compactarSiProcede()
+	}
+}
+
+// compactarSiProcede vuelca el estado actual a un snapshot y trunca el WAL
+// cuando este supera el umbral de compactación.
+func compactarSiProcede() {
+	mu.Lock()
+	defer mu.Unlock()
+	if wal.Len() < persist.DefaultCompactionThreshold {
+		return
+	}
+	cola := make([]persist.AvionRecord, colaAviones.Len())
+	for i, avion := range colaAviones {
+		cola[i] = avionToRecord(avion)
+	}
+	estado := persist.State{
+		Cola:              cola,
+		PistasDisponibles: len(pistasDisponibles),
+		EstadoActual:      estadoActual,
+	}
+	if err := wal.Compact(estado); err != nil {
+		logging.L.Error("wal_compact_failed", "error", err)
 	}
 }
 
@@ -236,24 +362,17 @@ func categoriaEstado(estado int) string {
 	return ""
 }
 
-// Verifica si un avión pertenece a la categoría válida para el estado actual
-func esCategoriaValida(avion Avion) bool {
-	switch estadoActual {
-	case 1:
-		return avion.categoria == "A"
-	case 2:
-		return avion.categoria == "B"
-	case 3:
-		return avion.categoria == "C"
-	}
-	return true
-}
-
 // Simula el uso de una pista por un avión
-func usarPista(avion Avion) {
+func usarPista(avion model.Avion) {
 	<-pistasDisponibles // Reserva una pista
-	fmt.Printf("Avión %d (%s) está usando una pista\n", avion.id, avion.categoria)
+	metrics.RunwayBusy.Set(float64(3 - len(pistasDisponibles)))
+	logWAL(persist.Entry{Type: persist.RunwayAcquired, Avion: avionToRecord(avion)})
+	logging.L.Info("plane_runway_acquired", "id", avion.ID, "cat", avion.Categoria, "state", estadoActual)
+	inicio := time.Now()
 	time.Sleep(time.Duration(rand.Intn(4)) * time.Second) // Simula tiempo de uso
-	fmt.Printf("Avión %d (%s) ha terminado de usar la pista\n", avion.id, avion.categoria)
+	metrics.RunwayServiceSeconds.Observe(time.Since(inicio).Seconds())
+	logging.L.Info("plane_runway_released", "id", avion.ID, "cat", avion.Categoria, "state", estadoActual)
+	logWAL(persist.Entry{Type: persist.RunwayReleased, Avion: avionToRecord(avion)})
 	pistasDisponibles <- struct{}{} // Libera la pista
+	metrics.RunwayBusy.Set(float64(3 - len(pistasDisponibles)))
 }
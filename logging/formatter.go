@@ -0,0 +1,41 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// JSONFormatter vuelca cada Record como una línea JSON, para que
+// herramientas de log ingieran el evento sin parsear texto libre.
+type JSONFormatter struct{}
+
+func (JSONFormatter) Format(rec Record) []byte {
+	entry := make(map[string]interface{}, len(rec.Fields)+3)
+	entry["time"] = rec.Time.Format(time.RFC3339Nano)
+	entry["level"] = rec.Level.String()
+	entry["msg"] = rec.Message
+	for _, f := range rec.Fields {
+		entry[f.Key] = f.Value
+	}
+	encoded, err := json.Marshal(entry)
+	if err != nil {
+		return []byte(fmt.Sprintf(`{"level":"ERROR","msg":"logging: failed to marshal record: %v"}`, err) + "\n")
+	}
+	return append(encoded, '\n')
+}
+
+// TextFormatter produce una línea legible por humanos:
+// "2026-07-27T10:00:00Z INFO  mensaje key=value ...".
+type TextFormatter struct{}
+
+func (TextFormatter) Format(rec Record) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s %-5s %s", rec.Time.Format(time.RFC3339), rec.Level, rec.Message)
+	for _, f := range rec.Fields {
+		fmt.Fprintf(&buf, " %s=%v", f.Key, f.Value)
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes()
+}
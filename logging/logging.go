@@ -0,0 +1,131 @@
+// Package logging provides leveled, structured logging for the cliente
+// process. Events carry key/value fields instead of a free-form string, so
+// operational tooling downstream can parse and filter them, and the output
+// actually reaches a writer instead of an unread buffer.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Level es la severidad de un evento de log.
+type Level int
+
+const (
+	DEBUG Level = iota
+	INFO
+	WARN
+	ERROR
+)
+
+func (l Level) String() string {
+	switch l {
+	case DEBUG:
+		return "DEBUG"
+	case INFO:
+		return "INFO"
+	case WARN:
+		return "WARN"
+	case ERROR:
+		return "ERROR"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Field es un par clave/valor adjunto a un Record.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Record es un evento de log ya resuelto, listo para formatear.
+type Record struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	Fields  []Field
+}
+
+// Formatter convierte un Record en los bytes que se escriben al output.
+type Formatter interface {
+	Format(rec Record) []byte
+}
+
+// Logger escribe los Records por encima de un nivel mínimo a uno o más
+// io.Writer, usando un Formatter compartido.
+type Logger struct {
+	mu        sync.Mutex
+	level     Level
+	formatter Formatter
+	outputs   []io.Writer
+}
+
+// New crea un Logger con el nivel mínimo, formatter y writers dados. Si no
+// se pasa ningún writer, se escribe a os.Stdout.
+func New(level Level, formatter Formatter, outputs ...io.Writer) *Logger {
+	if len(outputs) == 0 {
+		outputs = []io.Writer{os.Stdout}
+	}
+	return &Logger{level: level, formatter: formatter, outputs: outputs}
+}
+
+// L es el logger global usado por el resto del programa.
+var L = New(INFO, JSONFormatter{}, os.Stdout)
+
+// AddOutput agrega un writer adicional (p.ej. un hook de syslog o un fichero
+// rotado) a los destinos del logger.
+func (lg *Logger) AddOutput(w io.Writer) {
+	lg.mu.Lock()
+	defer lg.mu.Unlock()
+	lg.outputs = append(lg.outputs, w)
+}
+
+// Debug registra un evento de nivel DEBUG con pares clave/valor, p.ej.
+// L.Debug("heartbeat_sent", "seq", seq).
+func (lg *Logger) Debug(msg string, kv ...interface{}) { lg.log(DEBUG, msg, kv) }
+
+// Info registra un evento de nivel INFO.
+func (lg *Logger) Info(msg string, kv ...interface{}) { lg.log(INFO, msg, kv) }
+
+// Warn registra un evento de nivel WARN.
+func (lg *Logger) Warn(msg string, kv ...interface{}) { lg.log(WARN, msg, kv) }
+
+// Error registra un evento de nivel ERROR.
+func (lg *Logger) Error(msg string, kv ...interface{}) { lg.log(ERROR, msg, kv) }
+
+func (lg *Logger) log(level Level, msg string, kv []interface{}) {
+	if level < lg.level {
+		return
+	}
+	rec := Record{Time: time.Now(), Level: level, Message: msg, Fields: fieldsFromKV(kv)}
+	line := lg.formatter.Format(rec)
+
+	lg.mu.Lock()
+	defer lg.mu.Unlock()
+	for _, w := range lg.outputs {
+		w.Write(line)
+	}
+}
+
+// fieldsFromKV empareja los argumentos variádicos en Fields, tolerando un
+// número impar de argumentos (el último queda sin valor).
+func fieldsFromKV(kv []interface{}) []Field {
+	fields := make([]Field, 0, (len(kv)+1)/2)
+	for i := 0; i < len(kv); i += 2 {
+		key, ok := kv[i].(string)
+		if !ok {
+			key = fmt.Sprintf("%v", kv[i])
+		}
+		if i+1 < len(kv) {
+			fields = append(fields, Field{Key: key, Value: kv[i+1]})
+		} else {
+			fields = append(fields, Field{Key: key, Value: nil})
+		}
+	}
+	return fields
+}
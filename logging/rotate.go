@@ -0,0 +1,62 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// RotatingFileWriter escribe en un fichero local y lo rota (renombrándolo
+// con el sufijo .1, descartando la rotación anterior) al superar MaxBytes,
+// para que los logs persistidos en disco no crezcan sin límite.
+type RotatingFileWriter struct {
+	mu       sync.Mutex
+	path     string
+	maxBytes int64
+	f        *os.File
+	size     int64
+}
+
+// NewRotatingFileWriter abre (o crea) path para escritura con rotación al
+// superar maxBytes.
+func NewRotatingFileWriter(path string, maxBytes int64) (*RotatingFileWriter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("logging: open %s: %w", path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("logging: stat %s: %w", path, err)
+	}
+	return &RotatingFileWriter{path: path, maxBytes: maxBytes, f: f, size: info.Size()}, nil
+}
+
+func (w *RotatingFileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.size+int64(len(p)) > w.maxBytes {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.f.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *RotatingFileWriter) rotate() error {
+	if err := w.f.Close(); err != nil {
+		return fmt.Errorf("logging: close %s: %w", w.path, err)
+	}
+	if err := os.Rename(w.path, w.path+".1"); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("logging: rotate %s: %w", w.path, err)
+	}
+	f, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("logging: reopen %s: %w", w.path, err)
+	}
+	w.f = f
+	w.size = 0
+	return nil
+}
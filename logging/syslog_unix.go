@@ -0,0 +1,27 @@
+//go:build !windows
+
+package logging
+
+import "log/syslog"
+
+// SyslogWriter envía cada línea ya formateada al daemon syslog local, para
+// que los operadores puedan centralizar los logs sin tocar este proceso.
+type SyslogWriter struct {
+	w *syslog.Writer
+}
+
+// NewSyslogWriter abre una conexión al syslog local bajo el tag dado.
+func NewSyslogWriter(tag string) (*SyslogWriter, error) {
+	w, err := syslog.New(syslog.LOG_INFO|syslog.LOG_DAEMON, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &SyslogWriter{w: w}, nil
+}
+
+func (s *SyslogWriter) Write(p []byte) (int, error) {
+	if err := s.w.Info(string(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,78 @@
+// Package metrics expone el estado interno del cliente (profundidad de
+// cola, ocupación de pistas, transiciones de estado) como métricas
+// Prometheus, para poder ver desde fuera cómo de saturado está el
+// aeropuerto sin tener que leer los logs.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var (
+	// QueueDepth es el número de aviones esperando en la cola, por categoría.
+	QueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "airport",
+		Name:      "queue_depth",
+		Help:      "Número de aviones esperando en la cola de prioridad, por categoría.",
+	}, []string{"category"})
+
+	// RunwayBusy es cuántas de las 3 pistas están ocupadas ahora mismo.
+	RunwayBusy = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "airport",
+		Name:      "runway_busy",
+		Help:      "Número de pistas (0-3) actualmente en uso.",
+	})
+
+	// RunwayWaitSeconds es cuánto espera un avión en la cola antes de que se
+	// le asigne una pista.
+	RunwayWaitSeconds = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "airport",
+		Name:      "runway_wait_seconds",
+		Help:      "Tiempo de espera en la cola antes de asignar una pista.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	// RunwayServiceSeconds es cuánto tiempo ocupa un avión una pista.
+	RunwayServiceSeconds = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "airport",
+		Name:      "runway_service_seconds",
+		Help:      "Tiempo que un avión ocupa una pista.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	// StateTransitionsTotal cuenta las transiciones de estado del aeropuerto.
+	StateTransitionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "airport",
+		Name:      "state_transitions_total",
+		Help:      "Número de transiciones de estado del aeropuerto, por origen y destino.",
+	}, []string{"from", "to"})
+
+	// PlaneRejectedTotal cuenta los aviones rechazados, por motivo.
+	PlaneRejectedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "airport",
+		Name:      "plane_rejected_total",
+		Help:      "Número de aviones rechazados, por motivo.",
+	}, []string{"reason"})
+)
+
+func init() {
+	prometheus.MustRegister(
+		QueueDepth,
+		RunwayBusy,
+		RunwayWaitSeconds,
+		RunwayServiceSeconds,
+		StateTransitionsTotal,
+		PlaneRejectedTotal,
+	)
+}
+
+// Serve arranca un servidor HTTP que expone /metrics en addr. Bloquea, así
+// que se espera que el llamador lo lance en una goroutine.
+func Serve(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	return http.ListenAndServe(addr, mux)
+}
@@ -0,0 +1,52 @@
+// Package model contiene los tipos de datos compartidos por cliente y por
+// los paquetes que operan sobre su cola de aviones (scheduler, persist),
+// para evitar que package main sea la única fuente de verdad de su forma.
+package model
+
+import "time"
+
+// Avion representa un avión con sus atributos dentro de la cola de
+// prioridad.
+type Avion struct {
+	ID           int
+	Categoria    string
+	NumPasajeros int
+	Prioridad    int // Prioridad en la cola
+
+	// Encolado es el momento en que el avión entró a la cola; lo usa
+	// WeightedFairScheduler para no dejar pasajeros esperando indefinidamente.
+	Encolado time.Time
+
+	// Deadline es la hora límite de operación del avión; solo la usa
+	// DeadlineEDFScheduler y queda a cero para el resto de políticas.
+	Deadline time.Time
+}
+
+// State es el estado del aeropuerto relevante para decidir prioridades y
+// admisibilidad.
+type State struct {
+	Estado int
+}
+
+// AvionHeap implementa heap.Interface sobre []Avion, ordenado por Prioridad
+// descendente (la cabeza del heap es el avión de mayor prioridad).
+type AvionHeap []Avion
+
+func (h AvionHeap) Len() int           { return len(h) }
+func (h AvionHeap) Less(i, j int) bool { return h[i].Prioridad > h[j].Prioridad }
+func (h AvionHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+// Push agrega un avión a la cola.
+func (h *AvionHeap) Push(x interface{}) {
+	*h = append(*h, x.(Avion))
+}
+
+// Pop retira el avión en la última posición del slice subyacente (la
+// reordena container/heap antes de llamar a Pop).
+func (h *AvionHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[0 : n-1]
+	return x
+}
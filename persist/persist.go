@@ -0,0 +1,253 @@
+// Package persist gives the cliente process an append-only write-ahead log
+// plus periodic snapshots, so a crash no longer wipes the priority queue of
+// aviones or which runways were in use. Recovery works the etcd-style way:
+// load the last snapshot, then replay the WAL entries written after it.
+package persist
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	walFileName   = "wal.log"
+	heapFileName  = "heap.gob"
+	stateFileName = "state.gob"
+
+	// DefaultCompactionThreshold is how many WAL entries accumulate before
+	// Recover's caller should call Compact.
+	DefaultCompactionThreshold = 500
+)
+
+// EventType identifies the kind of state-changing event recorded in the WAL.
+type EventType int
+
+const (
+	PlaneEnqueued EventType = iota
+	PlaneDequeued
+	StateChanged
+	RunwayAcquired
+	RunwayReleased
+)
+
+// Entry is a single WAL record. Only the fields relevant to Type are
+// meaningful; the rest are left zero.
+type Entry struct {
+	Type    EventType
+	Avion   AvionRecord
+	Estado  int
+}
+
+// AvionRecord mirrors the fields of cliente's Avion struct that need to
+// survive a restart. It lives here, rather than importing cliente's type,
+// so persist has no dependency on package main.
+type AvionRecord struct {
+	ID           int
+	Categoria    string
+	NumPasajeros int
+	Prioridad    int
+	Encolado     time.Time
+	Deadline     time.Time
+}
+
+// State is the fully reconstructed in-memory state returned by Recover.
+type State struct {
+	Cola              []AvionRecord
+	PistasDisponibles int
+	EstadoActual      int
+}
+
+// WAL is an append-only log of Entry records. Each record is prefixed with
+// its length and a CRC32 of its payload, so a write torn by a process kill
+// is detected and dropped on the next replay instead of corrupting state.
+type WAL struct {
+	dir     string
+	f       *os.File
+	entries int
+}
+
+// OpenWAL opens (creating if necessary) the WAL file inside dir.
+func OpenWAL(dir string) (*WAL, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("persist: create dir: %w", err)
+	}
+	f, err := os.OpenFile(filepath.Join(dir, walFileName), os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("persist: open wal: %w", err)
+	}
+	return &WAL{dir: dir, f: f}, nil
+}
+
+// Append writes entry to the log. Callers should call this before acting on
+// the event it describes (e.g. before Pop/Push on the heap), so the WAL
+// never claims an event happened that the in-memory state doesn't reflect.
+func (w *WAL) Append(entry Entry) error {
+	var payload bytes.Buffer
+	if err := gob.NewEncoder(&payload).Encode(entry); err != nil {
+		return fmt.Errorf("persist: encode wal entry: %w", err)
+	}
+	var header [8]byte
+	binary.BigEndian.PutUint32(header[0:4], uint32(payload.Len()))
+	binary.BigEndian.PutUint32(header[4:8], crc32.ChecksumIEEE(payload.Bytes()))
+	if _, err := w.f.Write(header[:]); err != nil {
+		return fmt.Errorf("persist: write wal header: %w", err)
+	}
+	if _, err := w.f.Write(payload.Bytes()); err != nil {
+		return fmt.Errorf("persist: write wal payload: %w", err)
+	}
+	w.entries++
+	return nil
+}
+
+// Sync forces the WAL's recent writes to stable storage.
+func (w *WAL) Sync() error {
+	return w.f.Sync()
+}
+
+// Len reports how many entries have been appended since the last Compact
+// (or since the WAL was opened, if never compacted).
+func (w *WAL) Len() int {
+	return w.entries
+}
+
+// Compact snapshots the current state to heap.gob and state.gob, then
+// truncates the WAL: every entry it held is now captured by the snapshot.
+func (w *WAL) Compact(state State) error {
+	if err := writeGob(filepath.Join(w.dir, heapFileName), state.Cola); err != nil {
+		return err
+	}
+	stateSnapshot := struct {
+		PistasDisponibles int
+		EstadoActual      int
+	}{state.PistasDisponibles, state.EstadoActual}
+	if err := writeGob(filepath.Join(w.dir, stateFileName), stateSnapshot); err != nil {
+		return err
+	}
+	if err := w.f.Truncate(0); err != nil {
+		return fmt.Errorf("persist: truncate wal: %w", err)
+	}
+	if _, err := w.f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("persist: seek wal: %w", err)
+	}
+	w.entries = 0
+	return nil
+}
+
+// Recover loads the latest snapshot from dir, if any, then tail-applies the
+// WAL suffix on top of it to reconstruct the state exactly as it was before
+// the process stopped.
+func Recover(dir string) (*State, error) {
+	state := &State{}
+
+	var cola []AvionRecord
+	if err := readGob(filepath.Join(dir, heapFileName), &cola); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	state.Cola = cola
+
+	var stateSnapshot struct {
+		PistasDisponibles int
+		EstadoActual      int
+	}
+	if err := readGob(filepath.Join(dir, stateFileName), &stateSnapshot); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	state.PistasDisponibles = stateSnapshot.PistasDisponibles
+	state.EstadoActual = stateSnapshot.EstadoActual
+
+	entries, err := readWAL(filepath.Join(dir, walFileName))
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		applyEntry(state, entry)
+	}
+	return state, nil
+}
+
+func applyEntry(state *State, entry Entry) {
+	switch entry.Type {
+	case PlaneEnqueued:
+		state.Cola = append(state.Cola, entry.Avion)
+	case PlaneDequeued:
+		for i, a := range state.Cola {
+			if a.ID == entry.Avion.ID {
+				state.Cola = append(state.Cola[:i], state.Cola[i+1:]...)
+				break
+			}
+		}
+	case StateChanged:
+		state.EstadoActual = entry.Estado
+	case RunwayAcquired:
+		state.PistasDisponibles--
+	case RunwayReleased:
+		state.PistasDisponibles++
+	}
+}
+
+// readWAL replays every well-formed record in path. It stops at the first
+// short read or CRC mismatch, since that marks a write torn by a crash and
+// anything after it is unreliable.
+func readWAL(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("persist: open wal: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	for {
+		var header [8]byte
+		if _, err := io.ReadFull(f, header[:]); err != nil {
+			break
+		}
+		length := binary.BigEndian.Uint32(header[0:4])
+		wantCRC := binary.BigEndian.Uint32(header[4:8])
+
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(f, payload); err != nil {
+			break
+		}
+		if crc32.ChecksumIEEE(payload) != wantCRC {
+			break
+		}
+
+		var entry Entry
+		if err := gob.NewDecoder(bytes.NewReader(payload)).Decode(&entry); err != nil {
+			break
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func writeGob(path string, v interface{}) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("persist: create %s: %w", path, err)
+	}
+	defer f.Close()
+	if err := gob.NewEncoder(f).Encode(v); err != nil {
+		return fmt.Errorf("persist: encode %s: %w", path, err)
+	}
+	return nil
+}
+
+func readGob(path string, v interface{}) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return gob.NewDecoder(f).Decode(v)
+}
@@ -0,0 +1,60 @@
+package persist
+
+import (
+	"testing"
+)
+
+// TestRecoverAfterKillMidQueue simula la secuencia que pierde aviones si no
+// se loggea cada encolado: abrir el WAL, encolar varios aviones, procesar
+// solo algunos, y "matar" el proceso antes de que compacte (sin cerrar el
+// WAL de forma ordenada). Recover sobre el mismo directorio debe devolver
+// exactamente los aviones que seguían en cola.
+func TestRecoverAfterKillMidQueue(t *testing.T) {
+	dir := t.TempDir()
+
+	wal, err := OpenWAL(dir)
+	if err != nil {
+		t.Fatalf("OpenWAL: %v", err)
+	}
+
+	for id := 1; id <= 3; id++ {
+		if err := wal.Append(Entry{Type: PlaneEnqueued, Avion: AvionRecord{ID: id, Categoria: "A"}}); err != nil {
+			t.Fatalf("Append enqueue %d: %v", id, err)
+		}
+	}
+	// El avión 1 llega a usar una pista y se libera; el 2 y el 3 siguen en
+	// cola cuando "muere" el proceso.
+	if err := wal.Append(Entry{Type: PlaneDequeued, Avion: AvionRecord{ID: 1}}); err != nil {
+		t.Fatalf("Append dequeue: %v", err)
+	}
+	if err := wal.Append(Entry{Type: RunwayAcquired, Avion: AvionRecord{ID: 1}}); err != nil {
+		t.Fatalf("Append acquire: %v", err)
+	}
+	if err := wal.Append(Entry{Type: RunwayReleased, Avion: AvionRecord{ID: 1}}); err != nil {
+		t.Fatalf("Append release: %v", err)
+	}
+	if err := wal.Sync(); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	// No se llama a Compact: el heap.gob nunca llega a existir, como pasa
+	// en una ejecución real por debajo del umbral de compactación.
+
+	state, err := Recover(dir)
+	if err != nil {
+		t.Fatalf("Recover: %v", err)
+	}
+
+	if len(state.Cola) != 2 {
+		t.Fatalf("expected 2 aviones still queued after restart, got %d: %+v", len(state.Cola), state.Cola)
+	}
+	ids := map[int]bool{}
+	for _, a := range state.Cola {
+		ids[a.ID] = true
+	}
+	if !ids[2] || !ids[3] {
+		t.Fatalf("expected aviones 2 and 3 to survive the restart, got %+v", state.Cola)
+	}
+	if ids[1] {
+		t.Fatalf("avión 1 was dequeued before the kill and should not reappear, got %+v", state.Cola)
+	}
+}
@@ -0,0 +1,150 @@
+// Package scheduler desacopla la política de asignación de pistas (quién
+// puede operar ahora mismo y en qué orden) del bucle en cliente que la
+// aplica, para poder añadir o experimentar con políticas sin tocar ese bucle.
+package scheduler
+
+import (
+	"container/heap"
+	"os"
+	"time"
+
+	"practica4_ssoo_dist/model"
+)
+
+// Scheduler decide qué aviones pueden operar en el estado actual del
+// aeropuerto y en qué orden.
+type Scheduler interface {
+	// Priority calcula la prioridad de avion dado el estado actual; valores
+	// más altos salen antes de la cola.
+	Priority(avion model.Avion, state model.State) int
+	// Admissible indica si avion puede operar en el estado actual.
+	Admissible(avion model.Avion, state model.State) bool
+	// Reorder recalcula la prioridad de todos los aviones de h y los
+	// reordena en consecuencia.
+	Reorder(h *model.AvionHeap, state model.State)
+}
+
+// reorder es el bucle de pop/push compartido por las implementaciones: solo
+// cambia cómo se calcula la prioridad de cada avión.
+func reorder(h *model.AvionHeap, priority func(model.Avion) int) {
+	var nuevo model.AvionHeap
+	for h.Len() > 0 {
+		avion := heap.Pop(h).(model.Avion)
+		avion.Prioridad = priority(avion)
+		heap.Push(&nuevo, avion)
+	}
+	*h = nuevo
+}
+
+// CategoryPriorityScheduler reproduce el comportamiento original: en los
+// estados 1-3 solo opera la categoría correspondiente, y en los estados 4-6
+// esa categoría tiene prioridad sobre el resto.
+type CategoryPriorityScheduler struct{}
+
+func (CategoryPriorityScheduler) Priority(avion model.Avion, state model.State) int {
+	switch state.Estado {
+	case 1:
+		if avion.Categoria == "A" {
+			return 1
+		}
+	case 2:
+		if avion.Categoria == "B" {
+			return 1
+		}
+	case 3:
+		if avion.Categoria == "C" {
+			return 1
+		}
+	case 4:
+		if avion.Categoria == "A" {
+			return 2
+		}
+	case 5:
+		if avion.Categoria == "B" {
+			return 2
+		}
+	case 6:
+		if avion.Categoria == "C" {
+			return 2
+		}
+	}
+	return 0
+}
+
+func (CategoryPriorityScheduler) Admissible(avion model.Avion, state model.State) bool {
+	switch state.Estado {
+	case 1:
+		return avion.Categoria == "A"
+	case 2:
+		return avion.Categoria == "B"
+	case 3:
+		return avion.Categoria == "C"
+	}
+	return true
+}
+
+func (s CategoryPriorityScheduler) Reorder(h *model.AvionHeap, state model.State) {
+	reorder(h, func(avion model.Avion) int { return s.Priority(avion, state) })
+}
+
+// WeightedFairScheduler pondera la prioridad por el peso de la categoría, el
+// número de pasajeros y cuánto tiempo lleva esperando el avión, para que
+// ninguna categoría pueda acaparar las pistas indefinidamente.
+type WeightedFairScheduler struct {
+	CategoryWeight map[string]int
+}
+
+// NewWeightedFairScheduler crea un WeightedFairScheduler con los pesos por
+// defecto (A > B > C).
+func NewWeightedFairScheduler() *WeightedFairScheduler {
+	return &WeightedFairScheduler{CategoryWeight: map[string]int{"A": 3, "B": 2, "C": 1}}
+}
+
+func (s *WeightedFairScheduler) Priority(avion model.Avion, state model.State) int {
+	espera := 0
+	if !avion.Encolado.IsZero() {
+		espera = int(time.Since(avion.Encolado).Seconds())
+	}
+	return s.CategoryWeight[avion.Categoria]*10 + avion.NumPasajeros/20 + espera
+}
+
+func (s *WeightedFairScheduler) Admissible(avion model.Avion, state model.State) bool {
+	return true // el reparto justo ordena, no excluye categorías
+}
+
+func (s *WeightedFairScheduler) Reorder(h *model.AvionHeap, state model.State) {
+	reorder(h, func(avion model.Avion) int { return s.Priority(avion, state) })
+}
+
+// DeadlineEDFScheduler ordena por earliest-deadline-first: el avión cuyo
+// Deadline esté más próximo (o más vencido) sale primero.
+type DeadlineEDFScheduler struct{}
+
+func (DeadlineEDFScheduler) Priority(avion model.Avion, state model.State) int {
+	if avion.Deadline.IsZero() {
+		return 0
+	}
+	return -int(time.Until(avion.Deadline).Seconds())
+}
+
+func (DeadlineEDFScheduler) Admissible(avion model.Avion, state model.State) bool {
+	return true
+}
+
+func (s DeadlineEDFScheduler) Reorder(h *model.AvionHeap, state model.State) {
+	reorder(h, func(avion model.Avion) int { return s.Priority(avion, state) })
+}
+
+// FromEnv construye el Scheduler indicado por la variable de entorno
+// SCHEDULER_POLICY ("category", "weighted-fair" o "deadline-edf"). Si no
+// está definida o no se reconoce, se usa CategoryPriorityScheduler.
+func FromEnv() Scheduler {
+	switch os.Getenv("SCHEDULER_POLICY") {
+	case "weighted-fair":
+		return NewWeightedFairScheduler()
+	case "deadline-edf":
+		return DeadlineEDFScheduler{}
+	default:
+		return CategoryPriorityScheduler{}
+	}
+}